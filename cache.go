@@ -0,0 +1,186 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// offlineMode and refreshMode are set from the --offline/--refresh
+// flags in main and consulted by fetchCachedJSON.
+var (
+	offlineMode bool
+	refreshMode bool
+)
+
+// cacheEntry is what a cached provider persists to disk: the last
+// successful response body plus the validators needed to make a
+// conditional request next time.
+type cacheEntry struct {
+	Body         []ContentItem `json:"body"`
+	ETag         string        `json:"etag,omitempty"`
+	LastModified string        `json:"last_modified,omitempty"`
+	FetchedAt    time.Time     `json:"fetched_at"`
+}
+
+func cacheFile(name string) (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "stream", sanitizeCacheName(name)+".json"), nil
+}
+
+func sanitizeCacheName(name string) string {
+	if name == "" {
+		name = "content"
+	}
+	return strings.NewReplacer("/", "_", string(os.PathSeparator), "_").Replace(name)
+}
+
+func readCacheEntry(name string) (*cacheEntry, error) {
+	path, err := cacheFile(name)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var entry cacheEntry
+	if err := json.Unmarshal(body, &entry); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func writeCacheEntry(name string, entry *cacheEntry) error {
+	path, err := cacheFile(name)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, body, 0o644)
+}
+
+// fetchCachedJSON fetches url, reusing the on-disk cache stored under
+// name to make a conditional request and to serve stale data when the
+// network is unavailable or --offline is set. It returns the content
+// and how old that content is (zero when it came from a fresh 200).
+func fetchCachedJSON(ctx context.Context, name, url string) ([]ContentItem, time.Duration, error) {
+	cached, err := readCacheEntry(name)
+	if err != nil {
+		cached = nil // a corrupt cache file shouldn't block startup
+	}
+
+	if offlineMode {
+		if cached == nil {
+			return nil, 0, fmt.Errorf("%s: --offline set and no cached copy exists", name)
+		}
+		return cached.Body, time.Since(cached.FetchedAt), nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	if cached != nil && !refreshMode {
+		if cached.ETag != "" {
+			req.Header.Set("If-None-Match", cached.ETag)
+		}
+		if cached.LastModified != "" {
+			req.Header.Set("If-Modified-Since", cached.LastModified)
+		}
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		if cached != nil {
+			return cached.Body, time.Since(cached.FetchedAt), nil
+		}
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		return cached.Body, time.Since(cached.FetchedAt), nil
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		if cached != nil {
+			return cached.Body, time.Since(cached.FetchedAt), nil
+		}
+		return nil, 0, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		if cached != nil {
+			return cached.Body, time.Since(cached.FetchedAt), nil
+		}
+		return nil, 0, fmt.Errorf("%s: unexpected status %s", name, resp.Status)
+	}
+
+	var content []ContentItem
+	if err := json.Unmarshal(body, &content); err != nil {
+		return nil, 0, err
+	}
+
+	entry := &cacheEntry{
+		Body:         content,
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		FetchedAt:    time.Now(),
+	}
+	_ = writeCacheEntry(name, entry) // best-effort; a write failure shouldn't block the TUI
+
+	return content, 0, nil
+}
+
+// fetchCachedWith is fetchCachedJSON's counterpart for sources that
+// can't make a conditional HTTP request, such as RSS feeds: it still
+// honors --offline by serving the cache without calling fetch, and
+// falls back to the cache on a fetch error, but otherwise always
+// re-fetches since there's no ETag/Last-Modified to revalidate against.
+func fetchCachedWith(ctx context.Context, name string, fetch func(ctx context.Context) ([]ContentItem, error)) ([]ContentItem, time.Duration, error) {
+	cached, err := readCacheEntry(name)
+	if err != nil {
+		cached = nil // a corrupt cache file shouldn't block startup
+	}
+
+	if offlineMode {
+		if cached == nil {
+			return nil, 0, fmt.Errorf("%s: --offline set and no cached copy exists", name)
+		}
+		return cached.Body, time.Since(cached.FetchedAt), nil
+	}
+
+	content, err := fetch(ctx)
+	if err != nil {
+		if cached != nil {
+			return cached.Body, time.Since(cached.FetchedAt), nil
+		}
+		return nil, 0, err
+	}
+
+	_ = writeCacheEntry(name, &cacheEntry{Body: content, FetchedAt: time.Now()}) // best-effort
+	return content, 0, nil
+}
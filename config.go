@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the user-editable provider list loaded from
+// ~/.config/stream/config.yaml.
+type Config struct {
+	Providers []ProviderConfig `yaml:"providers"`
+}
+
+// ProviderConfig describes one entry under providers: in config.yaml.
+// Type selects which Provider Build produces; the remaining fields are
+// interpreted according to Type.
+type ProviderConfig struct {
+	Name string `yaml:"name"`
+	Type string `yaml:"type"` // github, http, file, or rss
+	URL  string `yaml:"url"`
+	Path string `yaml:"path"`
+}
+
+// Build turns a ProviderConfig into the Provider it describes.
+func (c ProviderConfig) Build() (Provider, error) {
+	switch c.Type {
+	case "github":
+		return NewGitHubProvider(), nil
+	case "http":
+		return &HTTPProvider{Label: c.Name, URL: c.URL}, nil
+	case "file":
+		return &FileProvider{Label: c.Name, Path: c.Path}, nil
+	case "rss":
+		return &RSSProvider{Label: c.Name, URL: c.URL}, nil
+	default:
+		return nil, fmt.Errorf("config: unknown provider type %q for %q", c.Type, c.Name)
+	}
+}
+
+// LoadConfig reads ~/.config/stream/config.yaml. A missing file isn't
+// an error: it returns a zero Config so callers fall back to defaults.
+func LoadConfig() (Config, error) {
+	path, err := configPath()
+	if err != nil {
+		return Config{}, err
+	}
+
+	body, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return Config{}, nil
+	}
+	if err != nil {
+		return Config{}, err
+	}
+
+	var cfg Config
+	if err := yaml.Unmarshal(body, &cfg); err != nil {
+		return Config{}, fmt.Errorf("config: parsing %s: %w", path, err)
+	}
+
+	return cfg, nil
+}
+
+func configPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "stream", "config.yaml"), nil
+}
+
+// BuildProviders returns the providers described by cfg, falling back
+// to the built-in GitHub catalogue when config.yaml has none configured.
+func (cfg Config) BuildProviders() ([]Provider, error) {
+	if len(cfg.Providers) == 0 {
+		return []Provider{NewGitHubProvider()}, nil
+	}
+
+	providers := make([]Provider, 0, len(cfg.Providers))
+	for _, pc := range cfg.Providers {
+		p, err := pc.Build()
+		if err != nil {
+			return nil, err
+		}
+		providers = append(providers, p)
+	}
+
+	return providers, nil
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// FuzzyMatch pairs a ContentItem with the score it received against a
+// query and the rune indices into Item.Name that the query matched, so
+// callers can highlight them.
+type FuzzyMatch struct {
+	Item      ContentItem
+	Score     int
+	Positions []int
+}
+
+const (
+	scoreMatch        = 16
+	scoreWordBoundary = 12
+	scoreConsecutive  = 8
+	scoreGapPenalty   = -2
+	scoreLeadingGap   = -1
+)
+
+// FuzzyFilter scores every choice against query and returns the
+// matches that contain all of query's runes in order, sorted by
+// descending score with an alphabetical tiebreak. An empty query
+// matches everything with a zero score and sort.SliceStable leaves
+// ties in their incoming order, so the caller's original order is
+// preserved.
+func FuzzyFilter(choices []ContentItem, query string) []FuzzyMatch {
+	matches := make([]FuzzyMatch, 0, len(choices))
+
+	for _, choice := range choices {
+		score, positions, ok := fuzzyScore(choice.Name, query)
+		if !ok {
+			continue
+		}
+		matches = append(matches, FuzzyMatch{Item: choice, Score: score, Positions: positions})
+	}
+
+	if query == "" {
+		return matches
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		return strings.ToLower(matches[i].Item.Name) < strings.ToLower(matches[j].Item.Name)
+	})
+
+	return matches
+}
+
+// fuzzyScore walks pattern's runes through candidate case-insensitively,
+// requiring every pattern rune to appear in candidate in order. It
+// awards bonuses for matches at word boundaries (start of string, after
+// a space/underscore/dash/dot, or a lower-to-upper case transition) and
+// for consecutive matches, and penalizes gaps between matches and
+// unmatched characters before the first match. ok is false when not
+// every pattern rune could be matched.
+func fuzzyScore(candidate, pattern string) (score int, positions []int, ok bool) {
+	if pattern == "" {
+		return 0, nil, true
+	}
+
+	cRunes := []rune(candidate)
+	pRunes := []rune(strings.ToLower(pattern))
+
+	positions = make([]int, 0, len(pRunes))
+	pIdx := 0
+	lastMatch := -1
+
+	for i, r := range cRunes {
+		if pIdx >= len(pRunes) {
+			break
+		}
+		if unicode.ToLower(r) != pRunes[pIdx] {
+			continue
+		}
+
+		s := scoreMatch
+		if isWordBoundary(cRunes, i) {
+			s += scoreWordBoundary
+		}
+		switch {
+		case lastMatch == i-1:
+			s += scoreConsecutive
+		case lastMatch >= 0:
+			s += scoreGapPenalty * (i - lastMatch - 1)
+		default:
+			s += scoreLeadingGap * i
+		}
+
+		score += s
+		positions = append(positions, i)
+		lastMatch = i
+		pIdx++
+	}
+
+	if pIdx < len(pRunes) {
+		return 0, nil, false
+	}
+
+	return score, positions, true
+}
+
+// isWordBoundary reports whether position i in runes starts a new word:
+// the beginning of the string, the character after a space/underscore/
+// dash/dot, or a lowercase-to-uppercase transition (camelCase).
+func isWordBoundary(runes []rune, i int) bool {
+	if i == 0 {
+		return true
+	}
+
+	switch runes[i-1] {
+	case ' ', '_', '-', '.':
+		return true
+	}
+
+	return unicode.IsLower(runes[i-1]) && unicode.IsUpper(runes[i])
+}
+
+// filterPositions drops any position at or beyond limit, for callers
+// that render a truncated prefix of the string positions were computed
+// against (e.g. a name clipped to fit a column width).
+func filterPositions(positions []int, limit int) []int {
+	filtered := make([]int, 0, len(positions))
+	for _, p := range positions {
+		if p < limit {
+			filtered = append(filtered, p)
+		}
+	}
+	return filtered
+}
+
+// highlightMatches renders name with the runes at positions wrapped in
+// style, leaving the rest untouched. style takes the variadic form
+// lipgloss.Style.Render uses, so callers can pass it directly.
+func highlightMatches(name string, positions []int, style func(...string) string) string {
+	if len(positions) == 0 {
+		return name
+	}
+
+	matched := make(map[int]bool, len(positions))
+	for _, p := range positions {
+		matched[p] = true
+	}
+
+	runes := []rune(name)
+	b := strings.Builder{}
+	for i, r := range runes {
+		if matched[i] {
+			b.WriteString(style(string(r)))
+		} else {
+			b.WriteRune(r)
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFuzzyScore(t *testing.T) {
+	tests := []struct {
+		name      string
+		candidate string
+		pattern   string
+		wantOk    bool
+		positions []int
+	}{
+		{name: "empty pattern matches everything", candidate: "Lord of the Rings", pattern: "", wantOk: true, positions: nil},
+		{name: "exact prefix", candidate: "Lord of the Rings", pattern: "lord", wantOk: true, positions: []int{0, 1, 2, 3}},
+		{name: "abbreviation spanning words", candidate: "Lord of the Rings", pattern: "lotr", wantOk: true, positions: []int{0, 1, 8, 12}},
+		{name: "case insensitive", candidate: "Star Trek", pattern: "ST", wantOk: true, positions: []int{0, 1}},
+		{name: "no match", candidate: "Star Trek", pattern: "xyz", wantOk: false, positions: nil},
+		{name: "out of order fails", candidate: "Star Trek", pattern: "ts", wantOk: false, positions: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, positions, ok := fuzzyScore(tt.candidate, tt.pattern)
+			if ok != tt.wantOk {
+				t.Fatalf("fuzzyScore(%q, %q) ok = %v, want %v", tt.candidate, tt.pattern, ok, tt.wantOk)
+			}
+			if ok && !reflect.DeepEqual(positions, tt.positions) {
+				t.Fatalf("fuzzyScore(%q, %q) positions = %v, want %v", tt.candidate, tt.pattern, positions, tt.positions)
+			}
+		})
+	}
+}
+
+func TestFuzzyScoreWordBoundaryBonus(t *testing.T) {
+	// "b" lands right after the underscore in "foo_bar" (a word
+	// boundary) but mid-word in "foobar"; the boundary match should
+	// score higher despite matching the same single rune.
+	boundaryScore, _, ok := fuzzyScore("foo_bar", "b")
+	if !ok {
+		t.Fatal("fuzzyScore(foo_bar, b) = not ok, want ok")
+	}
+
+	midWordScore, _, ok := fuzzyScore("foobar", "b")
+	if !ok {
+		t.Fatal("fuzzyScore(foobar, b) = not ok, want ok")
+	}
+
+	if boundaryScore <= midWordScore {
+		t.Fatalf("word-boundary match scored %d, want more than mid-word match's %d", boundaryScore, midWordScore)
+	}
+}
+
+func TestFuzzyFilter(t *testing.T) {
+	choices := []ContentItem{
+		{Name: "Zebra"},
+		{Name: "Apple"},
+		{Name: "Mango"},
+	}
+
+	t.Run("empty query preserves input order", func(t *testing.T) {
+		got := FuzzyFilter(choices, "")
+		if len(got) != len(choices) {
+			t.Fatalf("got %d matches, want %d", len(got), len(choices))
+		}
+		for i, m := range got {
+			if m.Item.Name != choices[i].Name {
+				t.Fatalf("match[%d] = %q, want %q", i, m.Item.Name, choices[i].Name)
+			}
+		}
+	})
+
+	t.Run("non-empty query sorts by descending score", func(t *testing.T) {
+		got := FuzzyFilter(choices, "a")
+		if len(got) != 3 {
+			t.Fatalf("got %d matches, want 3", len(got))
+		}
+		for i := 1; i < len(got); i++ {
+			if got[i-1].Score < got[i].Score {
+				t.Fatalf("matches not sorted by descending score: %+v", got)
+			}
+		}
+	})
+
+	t.Run("non-matches are dropped", func(t *testing.T) {
+		got := FuzzyFilter(choices, "zzz")
+		if len(got) != 0 {
+			t.Fatalf("got %d matches, want 0", len(got))
+		}
+	})
+}
+
+func TestHighlightMatches(t *testing.T) {
+	bold := func(strs ...string) string {
+		out := ""
+		for _, s := range strs {
+			out += "[" + s + "]"
+		}
+		return out
+	}
+
+	got := highlightMatches("Rings", []int{0, 2}, bold)
+	want := "[R]i[n]gs"
+	if got != want {
+		t.Fatalf("highlightMatches = %q, want %q", got, want)
+	}
+}
+
+func TestFilterPositions(t *testing.T) {
+	got := filterPositions([]int{0, 5, 27, 28, 29}, 27)
+	want := []int{0, 5}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("filterPositions = %v, want %v", got, want)
+	}
+}
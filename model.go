@@ -0,0 +1,332 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/atotto/clipboard"
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// mode tracks whether the user is browsing the list or typing into the
+// filter box.
+type mode int
+
+const (
+	modeBrowsing mode = iota
+	modeFiltering
+)
+
+// item adapts a ContentItem (plus the fuzzy match positions used to
+// highlight it) to the list.Item interface.
+type item struct {
+	ContentItem
+	positions []int
+}
+
+func (i item) FilterValue() string { return i.Name }
+
+type model struct {
+	list         list.Model
+	textInput    textinput.Model
+	choices      []ContentItem
+	mode         mode
+	status       string
+	err          error
+	loading      bool
+	cacheAge     time.Duration
+	providerErrs []*ProviderError
+}
+
+type msgContentReceived struct {
+	content []ContentItem
+	age     time.Duration
+	err     error
+	errs    []*ProviderError
+}
+
+type msgActionDone struct {
+	action string
+	err    error
+}
+
+func initialModel() model {
+	ti := textinput.NewModel()
+	ti.Placeholder = "Type to filter..."
+	ti.CharLimit = 256
+
+	l := list.New(nil, itemDelegate{}, 0, 0)
+	l.Title = "stream"
+	l.SetShowStatusBar(false)
+	l.SetShowHelp(false)
+	l.SetFilteringEnabled(false)
+
+	return model{
+		list:      l,
+		textInput: ti,
+		choices:   []ContentItem{},
+		mode:      modeBrowsing,
+		loading:   true,
+	}
+}
+
+func (m model) Init() tea.Cmd {
+	return loadContent()
+}
+
+func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.list.SetSize(msg.Width, msg.Height-4)
+		return m, nil
+
+	case tea.KeyMsg:
+		if m.mode == modeFiltering {
+			return m.updateFiltering(msg)
+		}
+		return m.updateBrowsing(msg)
+
+	case msgContentReceived:
+		if msg.err != nil {
+			m.err = msg.err
+			return m, nil
+		}
+
+		m.choices = msg.content
+		m.cacheAge = msg.age
+		m.providerErrs = msg.errs
+		m.loading = false
+		m.list.SetItems(toListItems(FuzzyFilter(m.choices, "")))
+		return m, nil
+
+	case msgActionDone:
+		if msg.err != nil {
+			m.status = fmt.Sprintf("%s failed: %v", msg.action, msg.err)
+		} else {
+			m.status = fmt.Sprintf("%s ok", msg.action)
+		}
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.list, cmd = m.list.Update(msg)
+	return m, cmd
+}
+
+func (m model) updateBrowsing(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c", "q":
+		return m, tea.Quit
+
+	case "/":
+		m.mode = modeFiltering
+		m.textInput.Focus()
+		return m, nil
+
+	case "enter":
+		selected, ok := m.selectedItem()
+		if !ok {
+			return m, nil
+		}
+		return m, playCmd(selected.Url)
+
+	case "o":
+		selected, ok := m.selectedItem()
+		if !ok {
+			return m, nil
+		}
+		return m, openCmd(selected.Url)
+
+	case "y":
+		selected, ok := m.selectedItem()
+		if !ok {
+			return m, nil
+		}
+		return m, copyCmd(selected.Url)
+	}
+
+	var cmd tea.Cmd
+	m.list, cmd = m.list.Update(msg)
+	return m, cmd
+}
+
+func (m model) updateFiltering(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc, tea.KeyEnter:
+		m.mode = modeBrowsing
+		m.textInput.Blur()
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.textInput, cmd = m.textInput.Update(msg)
+	m.list.SetItems(toListItems(FuzzyFilter(m.choices, m.textInput.Value())))
+	return m, cmd
+}
+
+func (m model) selectedItem() (ContentItem, bool) {
+	it, ok := m.list.SelectedItem().(item)
+	if !ok {
+		return ContentItem{}, false
+	}
+	return it.ContentItem, true
+}
+
+func (m model) View() string {
+	if m.err != nil {
+		return fmt.Sprintf("Error: %v", m.err)
+	}
+
+	b := strings.Builder{}
+	if m.mode == modeFiltering {
+		b.WriteString(m.textInput.View() + "\n")
+	}
+
+	if m.loading {
+		b.WriteString("[Loading...]\n")
+	} else {
+		b.WriteString(m.list.View())
+		b.WriteString("\n" + formatCacheAge(m.cacheAge))
+	}
+
+	if len(m.providerErrs) > 0 {
+		b.WriteString("\n" + formatProviderErrs(m.providerErrs))
+	}
+
+	if m.status != "" {
+		b.WriteString("\n" + m.status)
+	}
+
+	return b.String()
+}
+
+// formatCacheAge renders the footer note describing how stale the
+// catalogue is. Zero means it was just fetched fresh from the network.
+func formatCacheAge(age time.Duration) string {
+	switch {
+	case age <= 0:
+		return "catalogue: up to date"
+	case age < time.Minute:
+		return "catalogue: just now"
+	case age < time.Hour:
+		return fmt.Sprintf("catalogue: %dm old", int(age.Minutes()))
+	default:
+		return fmt.Sprintf("catalogue: %dh old", int(age.Hours()))
+	}
+}
+
+// formatProviderErrs renders the footer note listing providers that
+// failed during the last fetch, so a failure doesn't silently drop
+// content with no indication to the user.
+func formatProviderErrs(errs []*ProviderError) string {
+	parts := make([]string, len(errs))
+	for i, e := range errs {
+		parts[i] = e.Error()
+	}
+	return "provider errors: " + strings.Join(parts, "; ")
+}
+
+func toListItems(matches []FuzzyMatch) []list.Item {
+	items := make([]list.Item, len(matches))
+	for i, match := range matches {
+		items[i] = item{ContentItem: match.Item, positions: match.Positions}
+	}
+	return items
+}
+
+// playCmd suspends the TUI and hands the terminal to the configured
+// media player, resuming once playback ends. The player defaults to
+// mpv but can be overridden with $STREAM_PLAYER.
+func playCmd(url string) tea.Cmd {
+	player := os.Getenv("STREAM_PLAYER")
+	if player == "" {
+		player = "mpv"
+	}
+
+	c := exec.Command(player, "--force-window=yes", url)
+	return tea.ExecProcess(c, func(err error) tea.Msg {
+		return msgActionDone{action: "play", err: err}
+	})
+}
+
+// openCmd opens url in the browser named by $BROWSER.
+func openCmd(url string) tea.Cmd {
+	return func() tea.Msg {
+		browser := os.Getenv("BROWSER")
+		if browser == "" {
+			return msgActionDone{action: "open", err: fmt.Errorf("$BROWSER is not set")}
+		}
+		return msgActionDone{action: "open", err: exec.Command(browser, url).Start()}
+	}
+}
+
+// copyCmd copies url to the system clipboard.
+func copyCmd(url string) tea.Cmd {
+	return func() tea.Msg {
+		return msgActionDone{action: "copy", err: clipboard.WriteAll(url)}
+	}
+}
+
+var (
+	nameStyle      = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
+	highlightStyle = nameStyle.Copy().Bold(true).Underline(true)
+	yearStyle      = lipgloss.NewStyle().Foreground(lipgloss.Color("242"))
+	typeStyle      = lipgloss.NewStyle().Foreground(lipgloss.Color("39"))
+	urlStyle       = lipgloss.NewStyle().Foreground(lipgloss.Color("100"))
+	selectedMarker = lipgloss.NewStyle().Foreground(lipgloss.Color("205")).Render("> ")
+)
+
+const (
+	listNameWidth = 30
+	listYearWidth = 6
+	listTypeWidth = 10
+)
+
+// itemDelegate renders each row as "name | year | type | URL: url",
+// matching the column layout the plain-text view used before the
+// switch to bubbles/list.
+type itemDelegate struct{}
+
+func (d itemDelegate) Height() int                        { return 1 }
+func (d itemDelegate) Spacing() int                        { return 0 }
+func (d itemDelegate) Update(tea.Msg, *list.Model) tea.Cmd { return nil }
+
+func (d itemDelegate) Render(w io.Writer, l list.Model, index int, listItem list.Item) {
+	it, ok := listItem.(item)
+	if !ok {
+		return
+	}
+
+	runes := []rune(it.Name)
+	positions := it.positions
+	if len(runes) > listNameWidth {
+		positions = filterPositions(positions, listNameWidth-3)
+		runes = append(runes[:listNameWidth-3], '.', '.', '.')
+	}
+
+	name := string(runes)
+	if len(runes) < listNameWidth {
+		name += strings.Repeat(" ", listNameWidth-len(runes))
+	}
+	name = highlightMatches(name, positions, highlightStyle.Render)
+	name = nameStyle.Render(name)
+
+	year := yearStyle.Render(fmt.Sprintf("%-*d", listYearWidth, it.Year))
+	contentType := typeStyle.Render(fmt.Sprintf("%-*s", listTypeWidth, it.Type))
+	url := urlStyle.Render(it.Url)
+
+	line := fmt.Sprintf("%s | %s | %s | URL: %s", name, year, contentType, url)
+	prefix := "  "
+	if index == l.Index() {
+		prefix = selectedMarker
+	}
+
+	fmt.Fprint(w, prefix+line)
+}
@@ -0,0 +1,232 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+)
+
+// Provider fetches ContentItems from a single source. Implementations
+// must be safe to Fetch concurrently with other providers.
+type Provider interface {
+	Name() string
+	Fetch(ctx context.Context) ([]ContentItem, error)
+}
+
+// Ager is implemented by providers whose last Fetch may have been
+// served from a local cache instead of the network. The Aggregator
+// uses it to report catalogue staleness to the TUI.
+type Ager interface {
+	Age() time.Duration
+}
+
+// GitHubProvider fetches the project's built-in catalogue from a GitHub
+// raw URL, as the tool did before providers existed. Its response is
+// cached on disk and revalidated conditionally on subsequent fetches.
+type GitHubProvider struct {
+	URL string
+	age time.Duration
+}
+
+// NewGitHubProvider returns a GitHubProvider pointed at stream's own
+// content.json.
+func NewGitHubProvider() *GitHubProvider {
+	return &GitHubProvider{URL: "https://raw.githubusercontent.com/d-a-s-h-o/stream/master/content.json"}
+}
+
+func (p *GitHubProvider) Name() string { return "github" }
+
+func (p *GitHubProvider) Age() time.Duration { return p.age }
+
+func (p *GitHubProvider) Fetch(ctx context.Context) ([]ContentItem, error) {
+	content, age, err := fetchCachedJSON(ctx, p.Name(), p.URL)
+	if err != nil {
+		return nil, err
+	}
+	p.age = age
+	return content, nil
+}
+
+// HTTPProvider fetches an arbitrary URL serving JSON in the
+// []ContentItem shape. Like GitHubProvider, its response is cached and
+// revalidated conditionally.
+type HTTPProvider struct {
+	Label string
+	URL   string
+	age   time.Duration
+}
+
+func (p *HTTPProvider) Name() string { return p.Label }
+
+func (p *HTTPProvider) Age() time.Duration { return p.age }
+
+func (p *HTTPProvider) Fetch(ctx context.Context) ([]ContentItem, error) {
+	content, age, err := fetchCachedJSON(ctx, p.Name(), p.URL)
+	if err != nil {
+		return nil, err
+	}
+	p.age = age
+	return content, nil
+}
+
+// FileProvider reads a local file containing JSON in the
+// []ContentItem shape, for catalogues that don't warrant a server.
+type FileProvider struct {
+	Label string
+	Path  string
+}
+
+func (p *FileProvider) Name() string { return p.Label }
+
+func (p *FileProvider) Fetch(ctx context.Context) ([]ContentItem, error) {
+	body, err := ioutil.ReadFile(p.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	var content []ContentItem
+	if err := json.Unmarshal(body, &content); err != nil {
+		return nil, err
+	}
+
+	return content, nil
+}
+
+// RSSProvider maps the items of an RSS/Atom feed to ContentItems, one
+// per entry. Like the HTTP providers, its response is cached on disk so
+// --offline and a flaky connection fall back to the last fetch.
+type RSSProvider struct {
+	Label string
+	URL   string
+	age   time.Duration
+}
+
+func (p *RSSProvider) Name() string { return p.Label }
+
+func (p *RSSProvider) Age() time.Duration { return p.age }
+
+func (p *RSSProvider) Fetch(ctx context.Context) ([]ContentItem, error) {
+	content, age, err := fetchCachedWith(ctx, p.Name(), p.fetchFeed)
+	if err != nil {
+		return nil, err
+	}
+	p.age = age
+	return content, nil
+}
+
+func (p *RSSProvider) fetchFeed(ctx context.Context) ([]ContentItem, error) {
+	feed, err := gofeed.NewParser().ParseURLWithContext(p.URL, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]ContentItem, 0, len(feed.Items))
+	for _, entry := range feed.Items {
+		var year int
+		if entry.PublishedParsed != nil {
+			year = entry.PublishedParsed.Year()
+		}
+
+		items = append(items, ContentItem{
+			Name: entry.Title,
+			Year: year,
+			Type: "rss",
+			Url:  entry.Link,
+		})
+	}
+
+	return items, nil
+}
+
+// ProviderError records that one provider in an Aggregator failed,
+// without that failure taking down the rest of the fetch.
+type ProviderError struct {
+	Provider string
+	Err      error
+}
+
+func (e *ProviderError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Provider, e.Err)
+}
+
+func (e *ProviderError) Unwrap() error { return e.Err }
+
+// Aggregator fans Fetch out to every Provider concurrently, merges the
+// results, dedupes by URL, and reports per-provider failures instead of
+// letting one bad source take down the whole catalogue.
+type Aggregator struct {
+	Providers []Provider
+}
+
+func (a *Aggregator) Fetch(ctx context.Context) ([]ContentItem, time.Duration, []*ProviderError) {
+	var (
+		wg    sync.WaitGroup
+		mu    sync.Mutex
+		items []ContentItem
+		errs  []*ProviderError
+	)
+
+	for _, p := range a.Providers {
+		wg.Add(1)
+		go func(p Provider) {
+			defer wg.Done()
+
+			fetched, err := p.Fetch(ctx)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, &ProviderError{Provider: p.Name(), Err: err})
+				return
+			}
+			items = append(items, fetched...)
+		}(p)
+	}
+	wg.Wait()
+
+	items = dedupeByURL(items)
+	sort.Slice(items, func(i, j int) bool {
+		return strings.ToLower(items[i].Name) < strings.ToLower(items[j].Name)
+	})
+
+	// Report the oldest data any provider served, so a single stale
+	// cached source still shows up in the TUI's footer.
+	var age time.Duration
+	for _, p := range a.Providers {
+		if ager, ok := p.(Ager); ok {
+			if d := ager.Age(); d > age {
+				age = d
+			}
+		}
+	}
+
+	return items, age, errs
+}
+
+// dedupeByURL drops items whose URL repeats an earlier one. Items with
+// no URL (a feed entry with no <link>, say) aren't duplicates of one
+// another just because they share an empty key, so they all pass
+// through untouched.
+func dedupeByURL(items []ContentItem) []ContentItem {
+	seen := make(map[string]bool, len(items))
+	deduped := make([]ContentItem, 0, len(items))
+	for _, item := range items {
+		if item.Url == "" {
+			deduped = append(deduped, item)
+			continue
+		}
+		if seen[item.Url] {
+			continue
+		}
+		seen[item.Url] = true
+		deduped = append(deduped, item)
+	}
+	return deduped
+}
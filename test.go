@@ -0,0 +1,283 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/cheggaaa/pb/v3"
+	"github.com/mattn/go-isatty"
+	"golang.org/x/time/rate"
+)
+
+// stateFile is where --resume looks for (and every run saves) already
+// verified results, so a re-run only checks what's left.
+const stateFile = ".stream-checked.json"
+
+// checkResult is one link-checker verdict; it's the JSON shape used
+// both for --format=json output and for the resume state file.
+type checkResult struct {
+	Name      string `json:"name"`
+	Url       string `json:"url"`
+	Status    int    `json:"status"`
+	LatencyMs int64  `json:"latency_ms"`
+	FinalUrl  string `json:"final_url,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// runTest checks every catalogue URL with a worker pool and reports
+// which ones are dead. It's invoked as `stream test [flags]`, so its
+// flags are parsed separately from main's.
+func runTest() {
+	fs := flag.NewFlagSet("test", flag.ExitOnError)
+	concurrency := fs.Int("concurrency", 16, "number of URLs to check at once")
+	ratePerSec := fs.Float64("rate", 0, "max requests per second per host (0 = unlimited)")
+	format := fs.String("format", "json", "output format: json or table")
+	resume := fs.Bool("resume", false, "skip URLs already recorded in "+stateFile)
+	fs.Parse(flag.Args()[1:])
+
+	content, err := getContent()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error getting content: %v\n", err)
+		os.Exit(1)
+	}
+
+	done := map[string]checkResult{}
+	if *resume {
+		done = loadState()
+	}
+
+	pending := make([]ContentItem, 0, len(content))
+	results := make([]checkResult, 0, len(content))
+	for _, item := range content {
+		if r, ok := done[item.Url]; ok {
+			results = append(results, r)
+			continue
+		}
+		pending = append(pending, item)
+	}
+
+	var bar *pb.ProgressBar
+	if isatty.IsTerminal(os.Stdout.Fd()) {
+		bar = pb.StartNew(len(pending))
+	}
+
+	checker := &linkChecker{limit: *ratePerSec}
+	for _, r := range runChecks(pending, *concurrency, checker, bar) {
+		results = append(results, r)
+		done[r.Url] = r
+	}
+
+	if bar != nil {
+		bar.Finish()
+	}
+
+	saveState(done)
+	report(results, *format)
+}
+
+// runChecks fans pending out across concurrency workers and returns
+// their results in completion order.
+func runChecks(pending []ContentItem, concurrency int, checker *linkChecker, bar *pb.ProgressBar) []checkResult {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	jobs := make(chan ContentItem)
+	resultsCh := make(chan checkResult)
+
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for item := range jobs {
+				resultsCh <- checker.check(ctx, item)
+			}
+		}()
+	}
+
+	go func() {
+		for _, item := range pending {
+			jobs <- item
+		}
+		close(jobs)
+	}()
+
+	go func() {
+		wg.Wait()
+		close(resultsCh)
+	}()
+
+	results := make([]checkResult, 0, len(pending))
+	for r := range resultsCh {
+		results = append(results, r)
+		if bar != nil {
+			bar.Increment()
+		}
+	}
+	return results
+}
+
+// linkChecker checks URLs with a per-host rate limit shared across
+// workers.
+type linkChecker struct {
+	limit float64 // requests per second per host, 0 = unlimited
+
+	mu       sync.Mutex
+	limiters map[string]*rate.Limiter
+}
+
+func (c *linkChecker) limiterFor(host string) *rate.Limiter {
+	if c.limit <= 0 {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.limiters == nil {
+		c.limiters = make(map[string]*rate.Limiter)
+	}
+	l, ok := c.limiters[host]
+	if !ok {
+		l = rate.NewLimiter(rate.Limit(c.limit), 1)
+		c.limiters[host] = l
+	}
+	return l
+}
+
+// check does a HEAD request against item.Url, falling back to a
+// ranged GET when the server rejects HEAD, and records the outcome.
+func (c *linkChecker) check(ctx context.Context, item ContentItem) checkResult {
+	result := checkResult{Name: item.Name, Url: item.Url}
+
+	u, err := url.Parse(item.Url)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	if l := c.limiterFor(u.Host); l != nil {
+		if err := l.Wait(ctx); err != nil {
+			result.Error = err.Error()
+			return result
+		}
+	}
+
+	start := time.Now()
+	resp, err := probe(ctx, http.MethodHead, item.Url)
+	if err == nil && (resp.StatusCode == http.StatusMethodNotAllowed || resp.StatusCode == http.StatusForbidden) {
+		resp.Body.Close()
+		resp, err = probe(ctx, http.MethodGet, item.Url)
+	}
+	result.LatencyMs = time.Since(start).Milliseconds()
+
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	defer resp.Body.Close()
+
+	result.Status = resp.StatusCode
+	result.FinalUrl = resp.Request.URL.String()
+	return result
+}
+
+// probe issues method against target, ranging a GET to the first byte
+// so the fallback path doesn't download the whole response.
+func probe(ctx context.Context, method, target string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, target, nil)
+	if err != nil {
+		return nil, err
+	}
+	if method == http.MethodGet {
+		req.Header.Set("Range", "bytes=0-0")
+	}
+	return http.DefaultClient.Do(req)
+}
+
+// getContent loads the same providers the TUI does (config.yaml, or
+// just the built-in GitHub catalogue with none configured) and
+// aggregates their content, so `stream test` checks the catalogue a
+// user actually sees rather than a hard-coded second copy of it. Any
+// per-provider failures are logged to stderr; getContent only errors
+// outright when every provider failed.
+func getContent() ([]ContentItem, error) {
+	cfg, err := LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	providers, err := cfg.BuildProviders()
+	if err != nil {
+		return nil, err
+	}
+
+	agg := &Aggregator{Providers: providers}
+	content, _, errs := agg.Fetch(context.Background())
+	for _, e := range errs {
+		fmt.Fprintf(os.Stderr, "warning: %v\n", e)
+	}
+	if len(content) == 0 && len(errs) > 0 {
+		return nil, errs[0]
+	}
+
+	return content, nil
+}
+
+func loadState() map[string]checkResult {
+	body, err := ioutil.ReadFile(stateFile)
+	if err != nil {
+		return map[string]checkResult{}
+	}
+
+	var results []checkResult
+	if err := json.Unmarshal(body, &results); err != nil {
+		return map[string]checkResult{}
+	}
+
+	done := make(map[string]checkResult, len(results))
+	for _, r := range results {
+		done[r.Url] = r
+	}
+	return done
+}
+
+func saveState(done map[string]checkResult) {
+	results := make([]checkResult, 0, len(done))
+	for _, r := range done {
+		results = append(results, r)
+	}
+
+	body, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		return
+	}
+	_ = ioutil.WriteFile(stateFile, body, 0o644)
+}
+
+func report(results []checkResult, format string) {
+	if format == "table" {
+		for _, r := range results {
+			status := fmt.Sprintf("%d", r.Status)
+			if r.Error != "" {
+				status = "ERROR"
+			}
+			fmt.Printf("%-40s %-6s %6dms  %s\n", r.Name, status, r.LatencyMs, r.Url)
+		}
+		return
+	}
+
+	body, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error encoding results: %v\n", err)
+		return
+	}
+	fmt.Println(string(body))
+}